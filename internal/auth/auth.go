@@ -0,0 +1,100 @@
+// Package auth provides pluggable authentication backends for the proxy.
+//
+// An Auth backend gates incoming requests and supplies the token that
+// should be forwarded to the upstream. The backend is selected at startup
+// via a URL-style scheme, e.g. "file:///run/secrets/token",
+// "static://?token=abc123", "htpasswd:///etc/proxy/htpasswd", "none://" or
+// "cert://" (gate on a verified mTLS client certificate).
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Auth validates an incoming request and returns the token that should be
+// set on the outbound request's auth header. ok is false when the request
+// must be rejected; err carries the reason.
+type Auth interface {
+	Validate(r *http.Request) (token string, ok bool, err error)
+}
+
+// Closer is implemented by Auth backends that hold background resources
+// (file watcher goroutines, inotify descriptors) which must be released
+// once the backend is no longer in use, e.g. after a routes-file reload
+// replaces it with a fresh instance.
+type Closer interface {
+	Close() error
+}
+
+// New builds an Auth backend from a URL-style scheme. Supported schemes
+// are file://, static://, htpasswd://, basicfile://, none:// and cert://.
+func New(rawURL string) (Auth, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing auth url %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return NewFileAuth(pathFromURL(u))
+	case "static":
+		q := u.Query()
+		return NewStaticAuth(q.Get("token"), q.Get("user")), nil
+	case "htpasswd":
+		return NewHtpasswdAuth(pathFromURL(u))
+	case "basicfile":
+		return NewBasicFileAuth(pathFromURL(u))
+	case "none":
+		return NewNoneAuth(), nil
+	case "cert":
+		return NewCertAuth(), nil
+	default:
+		return nil, fmt.Errorf("unknown auth scheme %q", u.Scheme)
+	}
+}
+
+// FileBackend reports whether rawURL selects the file:// or basicfile://
+// auth scheme, i.e. a backend that reads its token/credentials file from
+// disk at startup. Callers like healthz use this to decide whether
+// checking that file's presence says anything about the selected backend.
+func FileBackend(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "file" || u.Scheme == "basicfile"
+}
+
+// pathFromURL recovers a filesystem path from a "scheme://path" or
+// "scheme://host/path" style URL, since relative paths land in Host
+// rather than Path.
+func pathFromURL(u *url.URL) string {
+	return u.Host + u.Path
+}
+
+// proxyBasicAuth extracts HTTP Basic credentials from the Proxy-Authorization
+// header, mirroring the stdlib's http.Request.BasicAuth (which only looks at
+// Authorization).
+func proxyBasicAuth(r *http.Request) (user, pass string, ok bool) {
+	const prefix = "Basic "
+
+	header := r.Header.Get("Proxy-Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(header[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+
+	user, pass, ok = strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", false
+	}
+	return user, pass, true
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewUnknownScheme(t *testing.T) {
+	if _, err := New("bogus://whatever"); err == nil {
+		t.Fatal("expected an error for an unknown auth scheme")
+	}
+}
+
+func TestFileAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.txt")
+	if err := os.WriteFile(path, []byte(" secret-token \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewFileAuth(path)
+	if err != nil {
+		t.Fatalf("NewFileAuth: %v", err)
+	}
+
+	token, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil || !ok {
+		t.Fatalf("Validate: token=%q ok=%v err=%v", token, ok, err)
+	}
+	if token != "secret-token" {
+		t.Fatalf("expected trimmed token, got %q", token)
+	}
+}
+
+func TestFileAuthEmptyIsRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.txt")
+	if err := os.WriteFile(path, []byte(""), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewFileAuth(path)
+	if err != nil {
+		t.Fatalf("NewFileAuth: %v", err)
+	}
+
+	if _, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil)); ok || err == nil {
+		t.Fatal("expected an empty token to be rejected")
+	}
+}
+
+func TestStaticAuth(t *testing.T) {
+	a := NewStaticAuth("abc123", "")
+	token, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil || !ok || token != "abc123" {
+		t.Fatalf("token=%q ok=%v err=%v", token, ok, err)
+	}
+}
+
+func TestStaticAuthWithUserProducesBasicHeader(t *testing.T) {
+	a := NewStaticAuth("abc123", "proxy")
+	token, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if token != "Basic cHJveHk6YWJjMTIz" {
+		t.Fatalf("unexpected basic auth token: %q", token)
+	}
+}
+
+func TestStaticAuthRejectsEmptyToken(t *testing.T) {
+	a := NewStaticAuth("", "")
+	if _, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil)); ok || err == nil {
+		t.Fatal("expected an empty static token to be rejected")
+	}
+}
+
+func TestNoneAuth(t *testing.T) {
+	a := NewNoneAuth()
+	token, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil || !ok || token != "" {
+		t.Fatalf("token=%q ok=%v err=%v", token, ok, err)
+	}
+}
+
+func TestBasicFileAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "basic.txt")
+	if err := os.WriteFile(path, []byte("alice:wonderland\n# comment\nbob:builder\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewBasicFileAuth(path)
+	if err != nil {
+		t.Fatalf("NewBasicFileAuth: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("alice", "wonderland")
+	req.Header.Set("Proxy-Authorization", req.Header.Get("Authorization"))
+
+	token, ok, err := a.Validate(req)
+	if err != nil || !ok {
+		t.Fatalf("token=%q ok=%v err=%v", token, ok, err)
+	}
+
+	bad := httptest.NewRequest(http.MethodGet, "/", nil)
+	bad.SetBasicAuth("alice", "wrong")
+	bad.Header.Set("Proxy-Authorization", bad.Header.Get("Authorization"))
+	if _, ok, err := a.Validate(bad); ok || err == nil {
+		t.Fatal("expected wrong credentials to be rejected")
+	}
+}
+
+func TestProxyBasicAuth(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("alice", "wonderland")
+	req.Header.Set("Proxy-Authorization", req.Header.Get("Authorization"))
+
+	user, pass, ok := proxyBasicAuth(req)
+	if !ok || user != "alice" || pass != "wonderland" {
+		t.Fatalf("user=%q pass=%q ok=%v", user, pass, ok)
+	}
+
+	if _, _, ok := proxyBasicAuth(httptest.NewRequest(http.MethodGet, "/", nil)); ok {
+		t.Fatal("expected missing header to fail")
+	}
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"my-proxy-service/internal/metrics"
+)
+
+// BasicFileAuth validates the Proxy-Authorization header against a plain
+// "user:password" per line file, and forwards the same credentials
+// upstream. Unlike HtpasswdAuth, passwords are stored in cleartext, which
+// is only appropriate for trusted, file-permission-protected deployments.
+type BasicFileAuth struct {
+	mu    sync.RWMutex
+	path  string
+	creds map[string]string
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// NewBasicFileAuth loads path and starts watching it for changes.
+func NewBasicFileAuth(path string) (*BasicFileAuth, error) {
+	a := &BasicFileAuth{path: path, stop: make(chan struct{})}
+	if err := a.reload(); err != nil {
+		return nil, err
+	}
+	go a.watch()
+	return a, nil
+}
+
+// Close stops the file watcher goroutine. Safe to call more than once.
+func (a *BasicFileAuth) Close() error {
+	a.once.Do(func() { close(a.stop) })
+	return nil
+}
+
+func (a *BasicFileAuth) Validate(r *http.Request) (string, bool, error) {
+	user, pass, ok := proxyBasicAuth(r)
+	if !ok {
+		return "", false, fmt.Errorf("missing or malformed Proxy-Authorization header")
+	}
+
+	a.mu.RLock()
+	want, known := a.creds[user]
+	a.mu.RUnlock()
+
+	if !known || subtle.ConstantTimeCompare([]byte(want), []byte(pass)) != 1 {
+		return "", false, fmt.Errorf("invalid proxy credentials for user %q", user)
+	}
+
+	return r.Header.Get("Proxy-Authorization"), true, nil
+}
+
+func (a *BasicFileAuth) reload() error {
+	f, err := os.Open(a.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	creds := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, pass, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		creds[user] = pass
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.creds = creds
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *BasicFileAuth) watch() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("auth: creating watcher for %s: %v", a.path, err)
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(a.path); err != nil {
+		log.Printf("auth: watching %s: %v", a.path, err)
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Write == fsnotify.Write {
+				log.Println("auth: basic credentials file modified, reloading...")
+				if err := a.reload(); err != nil {
+					log.Printf("auth: reloading %s: %v", a.path, err)
+				} else {
+					metrics.AuthReloads.WithLabelValues("basicfile").Inc()
+				}
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println("auth: watcher error:", err)
+		case <-a.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+)
+
+// CertAuth gates requests on a verified mTLS client certificate (enabled
+// via --tls-client-ca) and forwards the certificate's identity as the
+// outbound token: the CommonName, or if that's empty, the certificate's
+// first DNS or URI SAN.
+type CertAuth struct{}
+
+// NewCertAuth returns a CertAuth.
+func NewCertAuth() *CertAuth {
+	return &CertAuth{}
+}
+
+func (CertAuth) Validate(r *http.Request) (string, bool, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return "", false, fmt.Errorf("no verified client certificate presented")
+	}
+
+	identity := clientCertIdentity(r.TLS.PeerCertificates[0])
+	if identity == "" {
+		return "", false, fmt.Errorf("client certificate has no CommonName or SAN")
+	}
+	return identity, true, nil
+}
+
+// clientCertIdentity picks the CommonName, falling back to the first DNS
+// SAN and then the first URI SAN, for certificates (common with modern CAs)
+// that omit the CommonName and identify the subject via SAN only.
+func clientCertIdentity(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return ""
+}
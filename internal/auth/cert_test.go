@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCertAuthRejectsPlaintextRequest(t *testing.T) {
+	a := NewCertAuth()
+	if _, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil)); ok || err == nil {
+		t.Fatal("expected a request with no TLS state to be rejected")
+	}
+}
+
+func TestCertAuthAcceptsVerifiedClientCert(t *testing.T) {
+	a := NewCertAuth()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{
+			{Subject: pkix.Name{CommonName: "client.example.com"}},
+		},
+	}
+
+	token, ok, err := a.Validate(req)
+	if err != nil || !ok {
+		t.Fatalf("token=%q ok=%v err=%v", token, ok, err)
+	}
+	if token != "client.example.com" {
+		t.Fatalf("expected CommonName as token, got %q", token)
+	}
+}
+
+func TestCertAuthFallsBackToDNSSANWhenCommonNameEmpty(t *testing.T) {
+	a := NewCertAuth()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{
+			{DNSNames: []string{"svc.example.com", "other.example.com"}},
+		},
+	}
+
+	token, ok, err := a.Validate(req)
+	if err != nil || !ok {
+		t.Fatalf("token=%q ok=%v err=%v", token, ok, err)
+	}
+	if token != "svc.example.com" {
+		t.Fatalf("expected first DNS SAN as token, got %q", token)
+	}
+}
+
+func TestCertAuthRejectsCertWithNoIdentity(t *testing.T) {
+	a := NewCertAuth()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{}},
+	}
+
+	if _, ok, err := a.Validate(req); ok || err == nil {
+		t.Fatal("expected a certificate with no CommonName or SAN to be rejected")
+	}
+}
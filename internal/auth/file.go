@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"my-proxy-service/internal/metrics"
+)
+
+// FileAuth forwards the contents of a watched file as the outbound token.
+// This is the original behavior of the proxy, lifted out of cmd/main.
+type FileAuth struct {
+	mu    sync.RWMutex
+	path  string
+	token string
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// NewFileAuth reads path and starts watching it for changes.
+func NewFileAuth(path string) (*FileAuth, error) {
+	a := &FileAuth{path: path, stop: make(chan struct{})}
+	if err := a.reload(); err != nil {
+		return nil, err
+	}
+	go a.watch()
+	return a, nil
+}
+
+// Close stops the file watcher goroutine. Safe to call more than once.
+func (a *FileAuth) Close() error {
+	a.once.Do(func() { close(a.stop) })
+	return nil
+}
+
+func (a *FileAuth) Validate(r *http.Request) (string, bool, error) {
+	a.mu.RLock()
+	token := a.token
+	a.mu.RUnlock()
+
+	if token == "" {
+		return "", false, fmt.Errorf("authentication token is empty")
+	}
+	return token, true, nil
+}
+
+func (a *FileAuth) reload() error {
+	content, err := os.ReadFile(a.path)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.token = strings.TrimSpace(string(content))
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *FileAuth) watch() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("auth: creating watcher for %s: %v", a.path, err)
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(a.path); err != nil {
+		log.Printf("auth: watching %s: %v", a.path, err)
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Write == fsnotify.Write {
+				log.Println("auth: token file modified, reloading...")
+				if err := a.reload(); err != nil {
+					log.Printf("auth: reloading %s: %v", a.path, err)
+				} else {
+					metrics.AuthReloads.WithLabelValues("file").Inc()
+				}
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println("auth: watcher error:", err)
+		case <-a.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/tg123/go-htpasswd"
+
+	"my-proxy-service/internal/metrics"
+)
+
+// HtpasswdAuth validates the Proxy-Authorization header against an
+// htpasswd file and forwards the same credentials upstream. The file is
+// reloaded whenever it changes on disk.
+type HtpasswdAuth struct {
+	mu   sync.RWMutex
+	path string
+	file *htpasswd.File
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewHtpasswdAuth loads path as an htpasswd file and starts watching it.
+func NewHtpasswdAuth(path string) (*HtpasswdAuth, error) {
+	file, err := htpasswd.New(path, htpasswd.DefaultSystems, nil)
+	if err != nil {
+		return nil, fmt.Errorf("loading htpasswd file %s: %w", path, err)
+	}
+
+	a := &HtpasswdAuth{path: path, file: file, stop: make(chan struct{})}
+	go a.watch()
+	return a, nil
+}
+
+// Close stops the file watcher goroutine. Safe to call more than once.
+func (a *HtpasswdAuth) Close() error {
+	a.once.Do(func() { close(a.stop) })
+	return nil
+}
+
+func (a *HtpasswdAuth) Validate(r *http.Request) (string, bool, error) {
+	user, pass, ok := proxyBasicAuth(r)
+	if !ok {
+		return "", false, fmt.Errorf("missing or malformed Proxy-Authorization header")
+	}
+
+	a.mu.RLock()
+	file := a.file
+	a.mu.RUnlock()
+
+	if !file.Match(user, pass) {
+		return "", false, fmt.Errorf("invalid proxy credentials for user %q", user)
+	}
+
+	return r.Header.Get("Proxy-Authorization"), true, nil
+}
+
+func (a *HtpasswdAuth) watch() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("auth: creating watcher for %s: %v", a.path, err)
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(a.path); err != nil {
+		log.Printf("auth: watching %s: %v", a.path, err)
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Write == fsnotify.Write {
+				log.Println("auth: htpasswd file modified, reloading...")
+				file, err := htpasswd.New(a.path, htpasswd.DefaultSystems, nil)
+				if err != nil {
+					log.Printf("auth: reloading %s: %v", a.path, err)
+					continue
+				}
+				a.mu.Lock()
+				a.file = file
+				a.mu.Unlock()
+				metrics.AuthReloads.WithLabelValues("htpasswd").Inc()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println("auth: watcher error:", err)
+		case <-a.stop:
+			return
+		}
+	}
+}
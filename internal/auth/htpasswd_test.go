@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func writeHtpasswdFile(t *testing.T, path, user, password string) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hashing password: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(user+":"+string(hash)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func proxyAuthRequest(user, pass string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth(user, pass)
+	req.Header.Set("Proxy-Authorization", req.Header.Get("Authorization"))
+	return req
+}
+
+func TestHtpasswdAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "htpasswd")
+	writeHtpasswdFile(t, path, "alice", "wonderland")
+
+	a, err := NewHtpasswdAuth(path)
+	if err != nil {
+		t.Fatalf("NewHtpasswdAuth: %v", err)
+	}
+
+	token, ok, err := a.Validate(proxyAuthRequest("alice", "wonderland"))
+	if err != nil || !ok {
+		t.Fatalf("token=%q ok=%v err=%v", token, ok, err)
+	}
+	if token != proxyAuthRequest("alice", "wonderland").Header.Get("Proxy-Authorization") {
+		t.Fatalf("expected the original Proxy-Authorization header forwarded as the token, got %q", token)
+	}
+
+	if _, ok, err := a.Validate(proxyAuthRequest("alice", "wrong")); ok || err == nil {
+		t.Fatal("expected wrong credentials to be rejected")
+	}
+}
+
+func TestHtpasswdAuthMissingHeaderIsRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "htpasswd")
+	writeHtpasswdFile(t, path, "alice", "wonderland")
+
+	a, err := NewHtpasswdAuth(path)
+	if err != nil {
+		t.Fatalf("NewHtpasswdAuth: %v", err)
+	}
+
+	if _, ok, err := a.Validate(httptest.NewRequest(http.MethodGet, "/", nil)); ok || err == nil {
+		t.Fatal("expected a missing Proxy-Authorization header to be rejected")
+	}
+}
+
+func TestHtpasswdAuthReloadsOnFileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "htpasswd")
+	writeHtpasswdFile(t, path, "alice", "wonderland")
+
+	a, err := NewHtpasswdAuth(path)
+	if err != nil {
+		t.Fatalf("NewHtpasswdAuth: %v", err)
+	}
+
+	writeHtpasswdFile(t, path, "bob", "builder")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, ok, _ := a.Validate(proxyAuthRequest("bob", "builder"))
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the htpasswd file to be reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, ok, err := a.Validate(proxyAuthRequest("alice", "wonderland")); ok || err == nil {
+		t.Fatal("expected the replaced user to no longer be valid after reload")
+	}
+}
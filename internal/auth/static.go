@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+)
+
+// StaticAuth forwards a fixed, config-supplied token. If a user is set,
+// the token is forwarded as HTTP Basic credentials ("user:token"),
+// otherwise it is forwarded as-is.
+type StaticAuth struct {
+	value string
+}
+
+// NewStaticAuth builds a StaticAuth from a literal token and an optional
+// basic-auth username.
+func NewStaticAuth(token, user string) *StaticAuth {
+	value := token
+	if user != "" {
+		value = "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+token))
+	}
+	return &StaticAuth{value: value}
+}
+
+func (a *StaticAuth) Validate(r *http.Request) (string, bool, error) {
+	if a.value == "" {
+		return "", false, fmt.Errorf("static auth token is empty")
+	}
+	return a.value, true, nil
+}
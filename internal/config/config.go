@@ -1,59 +1,113 @@
 package config
 
 import (
-    "flag"
-    "log"
-    "os"
-    "strconv"
-    "sync"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
 )
 
-
 // Constants for routes
 var ROUTES = struct {
-    INDEX  string
-    HEALTH string
+	INDEX   string
+	HEALTH  string
+	METRICS string
 }{
-    INDEX:  "/",
-    HEALTH: "/healthz",
+	INDEX:   "/",
+	HEALTH:  "/healthz",
+	METRICS: "/metrics",
 }
 
 var (
-    Port            int
-    ApiFile         string
-    ProxyTarget     string
-    AuthTokenHeader string
-    mutex           sync.Mutex
+	Port                          int
+	ApiFile                       string
+	ProxyTarget                   string
+	AuthTokenHeader               string
+	AuthURL                       string
+	RoutesFile                    string
+	FlushIntervalMs               int
+	MaxIdleConnsPerHost           int
+	DialTimeoutMs                 int
+	TLSHandshakeTimeoutMs         int
+	ResponseHeaderTimeoutMs       int
+	IdleConnTimeoutMs             int
+	LogLevel                      string
+	AccessLogPath                 string
+	UpstreamHealthcheckIntervalMs int
+	ReadTimeoutMs                 int
+	WriteTimeoutMs                int
+	IdleTimeoutMs                 int
+	MaxHeaderBytes                int
+	ShutdownGracePeriodMs         int
+	TLSCert                       string
+	TLSKey                        string
+	TLSClientCA                   string
+	TLSMinVersion                 string
+	mutex                         sync.Mutex
 )
 
 func init() {
-    flag.IntVar(&Port, "port", 3000, "Port to run the proxy server on")
-    flag.StringVar(&ApiFile, "api-file", "file_to_watch.txt", "Path to the file containing the API key")
-    flag.StringVar(&ProxyTarget, "proxy-target", "http://example.com", "Target URL for proxying requests")
-    flag.StringVar(&AuthTokenHeader, "auth-token-header", "authorization", "Header name for authentication token")
+	flag.IntVar(&Port, "port", 3000, "Port to run the proxy server on")
+	flag.StringVar(&ApiFile, "api-file", "file_to_watch.txt", "Path to the file containing the API key")
+	flag.StringVar(&ProxyTarget, "proxy-target", "http://example.com", "Target URL for proxying requests")
+	flag.StringVar(&AuthTokenHeader, "auth-token-header", "authorization", "Header name for authentication token")
+	flag.StringVar(&AuthURL, "auth", "", "Auth backend URL: file://, static://?token=...&user=..., htpasswd://, basicfile://, none:// or cert://. Defaults to file://<api-file>")
+	flag.StringVar(&RoutesFile, "routes-file", "", "Path to a YAML/JSON file describing multi-target routing rules (overrides --proxy-target when a request matches)")
+	flag.IntVar(&FlushIntervalMs, "flush-interval", 0, "Milliseconds between flushes of a streamed response to the client; -1 flushes after every write (use for SSE/long-polling), 0 disables periodic flushing")
+	flag.IntVar(&MaxIdleConnsPerHost, "max-idle-conns-per-host", 100, "Max idle upstream connections kept open per host")
+	flag.IntVar(&DialTimeoutMs, "dial-timeout", 5000, "Milliseconds allowed to dial an upstream connection")
+	flag.IntVar(&TLSHandshakeTimeoutMs, "tls-handshake-timeout", 5000, "Milliseconds allowed for the upstream TLS handshake")
+	flag.IntVar(&ResponseHeaderTimeoutMs, "response-header-timeout", 0, "Milliseconds to wait for upstream response headers; 0 waits indefinitely")
+	flag.IntVar(&IdleConnTimeoutMs, "idle-conn-timeout", 90000, "Milliseconds an idle upstream connection is kept before closing")
+	flag.StringVar(&LogLevel, "log-level", "info", "Minimum log level: debug|info|warn|error")
+	flag.StringVar(&AccessLogPath, "access-log", "", "Path to a Combined-Log-Format access log file, rotated automatically; disabled when empty")
+	flag.IntVar(&UpstreamHealthcheckIntervalMs, "upstream-healthcheck-interval", 0, "Milliseconds between background HEAD probes of --proxy-target for /healthz; 0 disables the probe")
+	flag.IntVar(&ReadTimeoutMs, "read-timeout", 15000, "Milliseconds allowed to read an incoming request")
+	flag.IntVar(&WriteTimeoutMs, "write-timeout", 0, "Milliseconds allowed to write a response; 0 waits indefinitely (needed for long-lived streaming responses)")
+	flag.IntVar(&IdleTimeoutMs, "idle-timeout", 120000, "Milliseconds a keep-alive client connection is kept idle before closing")
+	flag.IntVar(&MaxHeaderBytes, "max-header-bytes", http.DefaultMaxHeaderBytes, "Max size of request headers the server will read")
+	flag.IntVar(&ShutdownGracePeriodMs, "shutdown-grace-period", 15000, "Milliseconds to wait for in-flight requests to finish on SIGINT/SIGTERM before closing connections")
+	flag.StringVar(&TLSCert, "tls-cert", "", "Path to a TLS certificate file; enables HTTPS when set")
+	flag.StringVar(&TLSKey, "tls-key", "", "Path to the TLS certificate's private key")
+	flag.StringVar(&TLSClientCA, "tls-client-ca", "", "Path to a CA bundle used to require and verify client certificates (mTLS); pair with -auth cert:// to authorize on the verified certificate")
+	flag.StringVar(&TLSMinVersion, "tls-min-version", "1.2", "Minimum TLS version to accept: 1.0, 1.1, 1.2 or 1.3")
 }
 
 func LoadConfig() {
-    flag.Parse()
-    setFlagFromEnv("PORT", &Port)
-    setFlagFromEnv("API_FILE", &ApiFile)
-    setFlagFromEnv("PROXY_TARGET", &ProxyTarget)
-    setFlagFromEnv("AUTH_TOKEN_HEADER", &AuthTokenHeader)
+	flag.Parse()
+	setFlagFromEnv("PORT", &Port)
+	setFlagFromEnv("API_FILE", &ApiFile)
+	setFlagFromEnv("PROXY_TARGET", &ProxyTarget)
+	setFlagFromEnv("AUTH_TOKEN_HEADER", &AuthTokenHeader)
+	setFlagFromEnv("AUTH", &AuthURL)
+	setFlagFromEnv("ROUTES_FILE", &RoutesFile)
+	setFlagFromEnv("LOG_LEVEL", &LogLevel)
+	setFlagFromEnv("ACCESS_LOG", &AccessLogPath)
+	setFlagFromEnv("TLS_CERT", &TLSCert)
+	setFlagFromEnv("TLS_KEY", &TLSKey)
+	setFlagFromEnv("TLS_CLIENT_CA", &TLSClientCA)
+	setFlagFromEnv("TLS_MIN_VERSION", &TLSMinVersion)
+
+	if AuthURL == "" {
+		AuthURL = "file://" + ApiFile
+	}
 }
 
 func setFlagFromEnv(envVar string, flagValue interface{}) {
-    if value := os.Getenv(envVar); value != "" {
-        switch v := flagValue.(type) {
-        case *int:
-            val, err := strconv.Atoi(value)
-            if err != nil {
-                log.Fatalf("Error parsing %s value: %v", envVar, err)
-            }
-            *v = val
-        case *string:
-            *v = value
-        default:
-            log.Fatalf("Unsupported flag type: %T", v)
-        }
-    }
+	if value := os.Getenv(envVar); value != "" {
+		switch v := flagValue.(type) {
+		case *int:
+			val, err := strconv.Atoi(value)
+			if err != nil {
+				log.Fatalf("Error parsing %s value: %v", envVar, err)
+			}
+			*v = val
+		case *string:
+			*v = value
+		default:
+			log.Fatalf("Unsupported flag type: %T", v)
+		}
+	}
 }
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Route describes one multi-target routing rule: requests whose host and
+// path match are dispatched to Target using the named AuthProfile.
+type Route struct {
+	HostPattern    *regexp.Regexp
+	PathPattern    *regexp.Regexp
+	Target         *url.URL
+	AuthProfile    string
+	StripPrefix    string
+	HeaderRewrites map[string]string
+}
+
+// routesFile is the on-disk shape of --routes-file, before compilation.
+type routesFile struct {
+	AuthProfiles map[string]string `yaml:"auth_profiles" json:"auth_profiles"`
+	Routes       []routeSpec       `yaml:"routes" json:"routes"`
+}
+
+type routeSpec struct {
+	HostPattern    string            `yaml:"host_pattern" json:"host_pattern"`
+	PathPattern    string            `yaml:"path_pattern" json:"path_pattern"`
+	Target         string            `yaml:"target" json:"target"`
+	AuthProfile    string            `yaml:"auth_profile" json:"auth_profile"`
+	StripPrefix    string            `yaml:"strip_prefix" json:"strip_prefix"`
+	HeaderRewrites map[string]string `yaml:"header_rewrites" json:"header_rewrites"`
+}
+
+// LoadRoutes reads and compiles the routing rules at path, returning the
+// ordered route list alongside the named auth-backend URLs referenced by
+// AuthProfile. The file may be YAML or JSON; format is picked by extension,
+// defaulting to YAML.
+func LoadRoutes(path string) ([]Route, map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading routes file %s: %w", path, err)
+	}
+
+	var rf routesFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &rf)
+	} else {
+		err = yaml.Unmarshal(content, &rf)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing routes file %s: %w", path, err)
+	}
+
+	routes := make([]Route, 0, len(rf.Routes))
+	for i, spec := range rf.Routes {
+		route, err := compileRoute(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, rf.AuthProfiles, nil
+}
+
+func compileRoute(spec routeSpec) (Route, error) {
+	route := Route{
+		AuthProfile:    spec.AuthProfile,
+		StripPrefix:    spec.StripPrefix,
+		HeaderRewrites: spec.HeaderRewrites,
+	}
+
+	if spec.Target == "" {
+		return Route{}, fmt.Errorf("target is required")
+	}
+	target, err := url.Parse(spec.Target)
+	if err != nil {
+		return Route{}, fmt.Errorf("parsing target %q: %w", spec.Target, err)
+	}
+	route.Target = target
+
+	if spec.HostPattern != "" {
+		hostPattern, err := regexp.Compile(spec.HostPattern)
+		if err != nil {
+			return Route{}, fmt.Errorf("compiling host_pattern %q: %w", spec.HostPattern, err)
+		}
+		route.HostPattern = hostPattern
+	}
+
+	if spec.PathPattern != "" {
+		pathPattern, err := regexp.Compile(spec.PathPattern)
+		if err != nil {
+			return Route{}, fmt.Errorf("compiling path_pattern %q: %w", spec.PathPattern, err)
+		}
+		route.PathPattern = pathPattern
+	}
+
+	return route, nil
+}
+
+// Matches reports whether r satisfies the route's host and path patterns.
+// An unset pattern matches everything.
+func (route Route) Matches(host, path string) bool {
+	if route.HostPattern != nil && !route.HostPattern.MatchString(host) {
+		return false
+	}
+	if route.PathPattern != nil && !route.PathPattern.MatchString(path) {
+		return false
+	}
+	return true
+}
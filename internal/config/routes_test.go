@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRoutesYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	content := `
+auth_profiles:
+  svcA: static://?token=abc123
+routes:
+  - host_pattern: "^api\\.example\\.com$"
+    path_pattern: "^/v1/"
+    target: "http://upstream-a:8080"
+    auth_profile: svcA
+    strip_prefix: /v1
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, profiles, err := LoadRoutes(path)
+	if err != nil {
+		t.Fatalf("LoadRoutes: %v", err)
+	}
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(routes))
+	}
+	if profiles["svcA"] != "static://?token=abc123" {
+		t.Fatalf("unexpected auth profile: %q", profiles["svcA"])
+	}
+
+	route := routes[0]
+	if !route.Matches("api.example.com", "/v1/widgets") {
+		t.Fatal("expected route to match")
+	}
+	if route.Matches("other.example.com", "/v1/widgets") {
+		t.Fatal("expected route not to match a different host")
+	}
+	if route.Target.String() != "http://upstream-a:8080" {
+		t.Fatalf("unexpected target: %s", route.Target)
+	}
+}
+
+func TestLoadRoutesRequiresTarget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := os.WriteFile(path, []byte("routes:\n  - path_pattern: \"/\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := LoadRoutes(path); err == nil {
+		t.Fatal("expected an error for a route with no target")
+	}
+}
@@ -0,0 +1,131 @@
+// Package healthz implements the service's /healthz endpoint: a JSON
+// readiness report made of independent checks.
+package healthz
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Check is the outcome of one readiness check.
+type Check struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Response is the JSON body returned by Checker.ServeHTTP.
+type Response struct {
+	Status string  `json:"status"`
+	Checks []Check `json:"checks"`
+}
+
+// Checker verifies the auth file is readable (when the selected auth
+// backend reads one) and, optionally, that the upstream target answers a
+// periodic HEAD probe.
+type Checker struct {
+	apiFile   string
+	checkFile bool
+	target    string
+	interval  time.Duration
+	client    *http.Client
+
+	mu          sync.RWMutex
+	upstreamErr error
+}
+
+// NewChecker builds a Checker for apiFile and target. checkFile should be
+// true only when the selected auth backend actually reads apiFile from
+// disk (file:// or basicfile://); other backends (static://, htpasswd://,
+// none://, cert://) don't use it, so asserting its presence would report
+// a correctly configured proxy as unhealthy. If interval is positive, a
+// background goroutine probes target with HEAD every interval and caches
+// the result for ServeHTTP.
+func NewChecker(apiFile, target string, checkFile bool, interval time.Duration) *Checker {
+	c := &Checker{
+		apiFile:   apiFile,
+		checkFile: checkFile,
+		target:    target,
+		interval:  interval,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+	if interval > 0 {
+		c.probeUpstream()
+		go c.probeLoop()
+	}
+	return c
+}
+
+func (c *Checker) probeLoop() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.probeUpstream()
+	}
+}
+
+func (c *Checker) probeUpstream() {
+	resp, err := c.client.Head(c.target)
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	c.mu.Lock()
+	c.upstreamErr = err
+	c.mu.Unlock()
+}
+
+func (c *Checker) checkAuthFile() Check {
+	f, err := os.Open(c.apiFile)
+	if err != nil {
+		return Check{Name: "auth_file", OK: false, Error: err.Error()}
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return Check{Name: "auth_file", OK: false, Error: err.Error()}
+	}
+	if info.Size() == 0 {
+		return Check{Name: "auth_file", OK: false, Error: "auth file is empty"}
+	}
+	return Check{Name: "auth_file", OK: true}
+}
+
+func (c *Checker) checkUpstream() Check {
+	c.mu.RLock()
+	err := c.upstreamErr
+	c.mu.RUnlock()
+
+	if err != nil {
+		return Check{Name: "upstream", OK: false, Error: err.Error()}
+	}
+	return Check{Name: "upstream", OK: true}
+}
+
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var checks []Check
+	if c.checkFile {
+		checks = append(checks, c.checkAuthFile())
+	}
+	if c.interval > 0 {
+		checks = append(checks, c.checkUpstream())
+	}
+
+	status := http.StatusOK
+	statusText := "ok"
+	for _, check := range checks {
+		if !check.OK {
+			status = http.StatusServiceUnavailable
+			statusText = "unavailable"
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Response{Status: statusText, Checks: checks})
+}
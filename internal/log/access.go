@@ -0,0 +1,49 @@
+package log
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// AccessLogger appends one Combined Log Format line per request to a
+// lumberjack-rotated file.
+type AccessLogger struct {
+	writer *lumberjack.Logger
+}
+
+// NewAccessLogger opens (creating if necessary) path for rotated access
+// logging.
+func NewAccessLogger(path string) *AccessLogger {
+	return &AccessLogger{writer: &lumberjack.Logger{Filename: path}}
+}
+
+// Close flushes and closes the underlying log file.
+func (a *AccessLogger) Close() error {
+	return a.writer.Close()
+}
+
+// Log appends one Combined Log Format line describing r's outcome.
+func (a *AccessLogger) Log(r *http.Request, status, bytes int, duration time.Duration) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	line := fmt.Sprintf("%s - - [%s] %q %d %d %q %q\n",
+		host,
+		time.Now().Format("02/Jan/2006:15:04:05 -0700"),
+		fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), r.Proto),
+		status,
+		bytes,
+		r.Referer(),
+		r.UserAgent(),
+	)
+
+	if _, err := a.writer.Write([]byte(line)); err != nil {
+		L().Error("writing access log", "error", err, "path", a.writer.Filename)
+	}
+}
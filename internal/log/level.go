@@ -0,0 +1,34 @@
+package log
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Level is a parsed --log-level/LOG_LEVEL value.
+type Level = slog.Level
+
+const (
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+)
+
+// ParseLevel maps a case-insensitive level name (debug|info|warn|error) to
+// a slog.Level, defaulting to LevelInfo for an empty string.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q", name)
+	}
+}
@@ -0,0 +1,34 @@
+// Package log provides the service's structured logging: a level-gated
+// slog wrapper, per-request IDs, and a Combined-Log-Format access logger.
+package log
+
+import (
+	"log/slog"
+	"os"
+)
+
+// CondLogger is a level-gated structured logger: records below the
+// configured level are dropped before formatting, same as slog itself,
+// but named to make that gating explicit at call sites.
+type CondLogger struct {
+	*slog.Logger
+}
+
+var std = New(LevelInfo)
+
+// New builds a CondLogger writing text-formatted records to stderr at or
+// above level.
+func New(level Level) *CondLogger {
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	return &CondLogger{Logger: slog.New(handler)}
+}
+
+// SetDefault installs logger as the package-level default returned by L.
+func SetDefault(logger *CondLogger) {
+	std = logger
+}
+
+// L returns the package-level default logger.
+func L() *CondLogger {
+	return std
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]Level{
+		"debug":   LevelDebug,
+		"INFO":    LevelInfo,
+		"":        LevelInfo,
+		"warn":    LevelWarn,
+		"warning": LevelWarn,
+		"Error":   LevelError,
+	}
+	for name, want := range cases {
+		got, err := ParseLevel(name)
+		if err != nil {
+			t.Fatalf("ParseLevel(%q): %v", name, err)
+		}
+		if got != want {
+			t.Fatalf("ParseLevel(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := ParseLevel("bogus"); err == nil {
+		t.Fatal("expected an error for an unknown level")
+	}
+}
+
+func TestRequestIDRoundTrip(t *testing.T) {
+	id := NewRequestID()
+	if len(id) != 16 {
+		t.Fatalf("expected a 16-character request id, got %q", id)
+	}
+
+	ctx := WithRequestID(context.Background(), id)
+	if got := RequestID(ctx); got != id {
+		t.Fatalf("RequestID = %q, want %q", got, id)
+	}
+}
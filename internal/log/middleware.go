@@ -0,0 +1,80 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// statusRecorder captures the status code and byte count written through
+// it, so Middleware can log them once the handler returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher by delegating to the wrapped
+// ResponseWriter, so ReverseProxy's flush-interval streaming still works
+// for requests that pass through this middleware.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker by delegating to the wrapped
+// ResponseWriter, so WebSocket/Upgrade proxying still works for requests
+// that pass through this middleware.
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("statusRecorder: underlying %T is not an http.Hijacker", w.ResponseWriter)
+	}
+	return hj.Hijack()
+}
+
+// Middleware assigns each request a request ID (echoed as X-Request-Id),
+// logs a structured summary line per request, and appends a
+// Combined-Log-Format line to access when non-nil.
+func Middleware(next http.Handler, access *AccessLogger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID := NewRequestID()
+		w.Header().Set(RequestIDHeader, reqID)
+		r = r.WithContext(WithRequestID(r.Context(), reqID))
+
+		rec := &statusRecorder{ResponseWriter: w}
+		start := time.Now()
+
+		next.ServeHTTP(rec, r)
+
+		duration := time.Since(start)
+		L().Info("request completed",
+			"req_id", reqID,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration_ms", duration.Milliseconds(),
+		)
+
+		if access != nil {
+			access.Log(r, rec.status, rec.bytes, duration)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package log
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader is echoed on every response carrying the request ID
+// generated for it.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random 16-character hex request ID.
+func NewRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// WithRequestID returns a copy of ctx carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored on ctx by WithRequestID, or ""
+// if none was stored.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
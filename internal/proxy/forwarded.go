@@ -0,0 +1,32 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+)
+
+// addForwardedHeaders sets X-Forwarded-Proto and X-Forwarded-Host on req
+// based on original, the incoming request before the Director rewrote it
+// for the upstream. X-Forwarded-For is deliberately left alone here:
+// httputil.ReverseProxy.ServeHTTP appends the client IP to it after the
+// Director runs, so setting it here too would duplicate the client IP.
+func addForwardedHeaders(req, original *http.Request) {
+	proto := "http"
+	if original.TLS != nil {
+		proto = "https"
+	}
+	req.Header.Set("X-Forwarded-Proto", proto)
+
+	if original.Host != "" {
+		req.Header.Set("X-Forwarded-Host", original.Host)
+	}
+}
+
+// stripPathPrefix removes prefix from the start of path, used for routes
+// configured with strip_prefix.
+func stripPathPrefix(path, prefix string) string {
+	if prefix == "" {
+		return path
+	}
+	return strings.TrimPrefix(path, prefix)
+}
@@ -0,0 +1,33 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+)
+
+// hopHeaders lists the hop-by-hop headers defined by RFC 7230 section 6.1.
+// These are meaningful only for a single transport-level connection and
+// must never be forwarded by a proxy.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailers",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// removeHopHeaders strips hop-by-hop headers from header in place, including
+// any additional headers named by a "Connection" header value.
+func removeHopHeaders(header http.Header) {
+	if connection := header.Get("Connection"); connection != "" {
+		for _, name := range strings.Split(connection, ",") {
+			header.Del(strings.TrimSpace(name))
+		}
+	}
+	for _, h := range hopHeaders {
+		header.Del(h)
+	}
+}
@@ -0,0 +1,26 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRemoveHopHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Connection", "X-Custom")
+	h.Set("X-Custom", "keep-me-out")
+	h.Set("Keep-Alive", "timeout=5")
+	h.Set("Transfer-Encoding", "chunked")
+	h.Set("X-App-Header", "keep")
+
+	removeHopHeaders(h)
+
+	for _, hop := range []string{"Connection", "Keep-Alive", "Transfer-Encoding", "X-Custom"} {
+		if h.Get(hop) != "" {
+			t.Fatalf("expected %s to be stripped, got %q", hop, h.Get(hop))
+		}
+	}
+	if h.Get("X-App-Header") != "keep" {
+		t.Fatal("expected non-hop-by-hop header to survive")
+	}
+}
@@ -1,61 +1,89 @@
 package proxy
 
 import (
-    "fmt"
-    "io"
-    "log"
-    "net/http"
-    "strings"
-
-    "my-proxy-service/internal/config"
-    "my-proxy-service/internal/utils"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"my-proxy-service/internal/auth"
+	"my-proxy-service/internal/config"
+	applog "my-proxy-service/internal/log"
+	"my-proxy-service/internal/metrics"
+	"my-proxy-service/internal/utils"
 )
 
-func HandleProxy(w http.ResponseWriter, r *http.Request) {
-    log.Printf("Incoming request: %s %s", r.Method, r.URL)
-
-    targetURL := config.ProxyTarget
-    if r.URL.String() != "/" {
-        targetURL += r.URL.String()
-    }
-    log.Printf("Target URL: %s", targetURL)
-
-    req, err := http.NewRequest(r.Method, targetURL, r.Body)
-    if err != nil {
-        utils.HandleError(w, err, http.StatusInternalServerError)
-        return
-    }
-
-    authTokenValue, err := utils.ReadAuthToken()
-    if err != nil {
-        utils.HandleError(w, err, http.StatusInternalServerError)
-        return
-    }
-    authTokenValue = strings.TrimSpace(authTokenValue)
-
-    if len(authTokenValue) == 0 {
-        log.Println("Authentication token is empty")
-        utils.HandleError(w, fmt.Errorf("authentication token is empty"), http.StatusUnauthorized)
-        return
-    }
-
-    req.Header.Set(config.AuthTokenHeader, authTokenValue)
-
-    client := &http.Client{}
-    resp, err := client.Do(req)
-    if err != nil {
-        utils.HandleError(w, err, http.StatusInternalServerError)
-        return
-    }
-    defer resp.Body.Close()
-
-    log.Printf("Response status: %s", resp.Status)
-
-    utils.CopyHeaders(w, resp)
-    w.WriteHeader(resp.StatusCode)
-    _, err = io.Copy(w, resp.Body)
-    if err != nil {
-        utils.HandleError(w, err, http.StatusInternalServerError)
-        return
-    }
+// Proxy forwards incoming requests to the configured upstream target,
+// gating them through an Auth backend that also supplies the token
+// forwarded upstream.
+type Proxy struct {
+	Auth    auth.Auth
+	target  *url.URL
+	reverse *httputil.ReverseProxy
+}
+
+// New builds a Proxy backed by the given Auth, targeting config.ProxyTarget.
+func New(a auth.Auth) (*Proxy, error) {
+	target, err := url.Parse(config.ProxyTarget)
+	if err != nil {
+		return nil, fmt.Errorf("parsing proxy target %q: %w", config.ProxyTarget, err)
+	}
+
+	return &Proxy{Auth: a, target: target, reverse: buildReverseProxy(target)}, nil
+}
+
+func (p *Proxy) HandleProxy(w http.ResponseWriter, r *http.Request) {
+	applog.L().Debug("incoming request",
+		"req_id", applog.RequestID(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+		"upstream", p.target.String(),
+	)
+
+	token, ok, err := p.Auth.Validate(r)
+	if err != nil {
+		utils.HandleError(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	if !ok {
+		utils.HandleError(w, r, fmt.Errorf("request not authorized"), http.StatusUnauthorized)
+		return
+	}
+	if token != "" {
+		r.Header.Set(config.AuthTokenHeader, token)
+	}
+
+	p.reverse.ServeHTTP(w, r)
+}
+
+// buildReverseProxy builds a ReverseProxy targeting target, sharing this
+// package's connection pool and streaming settings, and handling
+// hop-by-hop headers and X-Forwarded-* on both directions per RFC 7230.
+func buildReverseProxy(target *url.URL) *httputil.ReverseProxy {
+	baseDirector := httputil.NewSingleHostReverseProxy(target).Director
+
+	rp := &httputil.ReverseProxy{
+		Transport:     getSharedTransport(),
+		FlushInterval: flushInterval(),
+	}
+
+	rp.Director = func(req *http.Request) {
+		original := req.Clone(req.Context())
+		baseDirector(req)
+		removeHopHeaders(req.Header)
+		addForwardedHeaders(req, original)
+		metrics.SetRouteLabel(req, target.String())
+	}
+
+	rp.ModifyResponse = func(resp *http.Response) error {
+		removeHopHeaders(resp.Header)
+		return nil
+	}
+
+	rp.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		metrics.UpstreamErrors.WithLabelValues(target.String()).Inc()
+		utils.HandleError(w, r, err, http.StatusBadGateway)
+	}
+
+	return rp
 }
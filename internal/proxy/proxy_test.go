@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"my-proxy-service/internal/auth"
+	"my-proxy-service/internal/config"
+)
+
+// withProxyTarget points config.ProxyTarget at url for the duration of the
+// test, restoring the previous value on cleanup.
+func withProxyTarget(t *testing.T, url string) {
+	t.Helper()
+	prev := config.ProxyTarget
+	config.ProxyTarget = url
+	t.Cleanup(func() { config.ProxyTarget = prev })
+}
+
+func TestHandleProxyStripsHopHeadersAndSetsForwardedHeaders(t *testing.T) {
+	var gotHeader http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	withProxyTarget(t, upstream.URL)
+
+	p, err := New(auth.NewNoneAuth())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	req.Header.Set("Connection", "X-Drop-Me")
+	req.Header.Set("X-Drop-Me", "should not arrive")
+	req.Header.Set("X-Keep-Me", "should arrive")
+	rr := httptest.NewRecorder()
+
+	p.HandleProxy(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d", rr.Code)
+	}
+	if gotHeader.Get("Connection") != "" {
+		t.Fatal("expected the Connection header itself to be stripped")
+	}
+	if gotHeader.Get("X-Drop-Me") != "" {
+		t.Fatal("expected the hop-by-hop header named by Connection to be stripped")
+	}
+	if gotHeader.Get("X-Keep-Me") != "should arrive" {
+		t.Fatalf("expected X-Keep-Me to reach upstream, got %q", gotHeader.Get("X-Keep-Me"))
+	}
+	if gotHeader.Get("X-Forwarded-Proto") != "http" {
+		t.Fatalf("X-Forwarded-Proto = %q, want http", gotHeader.Get("X-Forwarded-Proto"))
+	}
+	if gotHeader.Get("X-Forwarded-Host") == "" {
+		t.Fatal("expected X-Forwarded-Host to be set")
+	}
+	if gotHeader.Get("X-Forwarded-For") == "" {
+		t.Fatal("expected X-Forwarded-For to be set by httputil.ReverseProxy")
+	}
+}
+
+func TestHandleProxyForwardsAuthToken(t *testing.T) {
+	var gotHeader http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	withProxyTarget(t, upstream.URL)
+
+	prevHeader := config.AuthTokenHeader
+	config.AuthTokenHeader = "X-Api-Token"
+	t.Cleanup(func() { config.AuthTokenHeader = prevHeader })
+
+	p, err := New(auth.NewStaticAuth("abc123", ""))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	p.HandleProxy(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d", rr.Code)
+	}
+	if gotHeader.Get("X-Api-Token") != "abc123" {
+		t.Fatalf("X-Api-Token = %q, want abc123", gotHeader.Get("X-Api-Token"))
+	}
+}
+
+func TestHandleProxyRejectsUnauthorizedRequest(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("upstream must not be reached for an unauthorized request")
+	}))
+	defer upstream.Close()
+	withProxyTarget(t, upstream.URL)
+
+	p, err := New(auth.NewStaticAuth("", ""))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	p.HandleProxy(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,222 @@
+package proxy
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"my-proxy-service/internal/auth"
+	"my-proxy-service/internal/config"
+	applog "my-proxy-service/internal/log"
+	"my-proxy-service/internal/utils"
+)
+
+// Router dispatches requests across many upstream targets according to an
+// ordered list of routing rules loaded from --routes-file, falling back to
+// the single-target Proxy when nothing matches. Routes and their auth
+// profiles are reloaded whenever the routes file changes on disk.
+type Router struct {
+	mu          sync.RWMutex
+	entries     []routeEntry
+	authByName  map[string]authProfile
+	defaultAuth auth.Auth
+	fallback    *Proxy
+	routesPath  string
+}
+
+// routeEntry pairs a compiled route with its resolved auth backend and the
+// reverse proxy built for its target.
+type routeEntry struct {
+	route config.Route
+	auth  auth.Auth
+	proxy *httputil.ReverseProxy
+}
+
+// authProfile remembers the raw auth URL an auth_profiles entry was built
+// from, so a later reload can tell whether the profile actually changed
+// and reuse the existing backend (and its watcher goroutine) instead of
+// leaking a new one.
+type authProfile struct {
+	rawURL string
+	auth   auth.Auth
+}
+
+// NewRouter loads routesPath and starts watching it for changes. defaultAuth
+// is used both for the --proxy-target fallback and for any route that does
+// not set an auth_profile.
+func NewRouter(routesPath string, defaultAuth auth.Auth) (*Router, error) {
+	fallback, err := New(defaultAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	router := &Router{
+		defaultAuth: defaultAuth,
+		fallback:    fallback,
+		routesPath:  routesPath,
+	}
+
+	if err := router.reload(); err != nil {
+		return nil, err
+	}
+	go router.watch()
+	return router, nil
+}
+
+func (router *Router) reload() error {
+	routes, profiles, err := config.LoadRoutes(router.routesPath)
+	if err != nil {
+		return err
+	}
+
+	router.mu.RLock()
+	prevAuthByName := router.authByName
+	router.mu.RUnlock()
+
+	authByName := make(map[string]authProfile, len(profiles))
+	var created []auth.Auth
+	for name, rawURL := range profiles {
+		if prev, ok := prevAuthByName[name]; ok && prev.rawURL == rawURL {
+			authByName[name] = prev
+			continue
+		}
+		a, err := auth.New(rawURL)
+		if err != nil {
+			closeAuthBackends(created)
+			return err
+		}
+		created = append(created, a)
+		authByName[name] = authProfile{rawURL: rawURL, auth: a}
+	}
+
+	entries := make([]routeEntry, len(routes))
+	for i, route := range routes {
+		entries[i] = routeEntry{
+			route: route,
+			auth:  router.authFor(route, authByName),
+			proxy: buildReverseProxy(route.Target),
+		}
+	}
+
+	router.mu.Lock()
+	router.entries = entries
+	router.authByName = authByName
+	router.mu.Unlock()
+
+	// Release the backends (and their file watcher goroutines) that this
+	// reload replaced or dropped, now that nothing references them.
+	for name, prev := range prevAuthByName {
+		if cur, ok := authByName[name]; ok && cur.rawURL == prev.rawURL {
+			continue
+		}
+		closeAuthBackends([]auth.Auth{prev.auth})
+	}
+	return nil
+}
+
+func closeAuthBackends(backends []auth.Auth) {
+	for _, a := range backends {
+		if closer, ok := a.(auth.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Printf("router: closing auth backend: %v", err)
+			}
+		}
+	}
+}
+
+func (router *Router) authFor(route config.Route, authByName map[string]authProfile) auth.Auth {
+	if route.AuthProfile != "" {
+		if a, ok := authByName[route.AuthProfile]; ok {
+			return a.auth
+		}
+		log.Printf("router: route for target %s references unknown auth_profile %q, using default auth", route.Target, route.AuthProfile)
+	}
+	return router.defaultAuth
+}
+
+func (router *Router) watch() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("router: creating watcher for %s: %v", router.routesPath, err)
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(router.routesPath); err != nil {
+		log.Printf("router: watching %s: %v", router.routesPath, err)
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Write == fsnotify.Write {
+				log.Println("router: routes file modified, reloading...")
+				if err := router.reload(); err != nil {
+					log.Printf("router: reloading %s: %v", router.routesPath, err)
+				}
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println("router: watcher error:", err)
+		}
+	}
+}
+
+// ServeHTTP dispatches r to the first matching route, gating it through
+// that route's auth backend before handing off to its reverse proxy. It
+// falls back to the single-target Proxy when no route matches.
+func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router.mu.RLock()
+	entries := router.entries
+	router.mu.RUnlock()
+
+	for _, entry := range entries {
+		if !entry.route.Matches(r.Host, r.URL.Path) {
+			continue
+		}
+
+		applog.L().Debug("incoming request",
+			"req_id", applog.RequestID(r.Context()),
+			"method", r.Method,
+			"path", r.URL.Path,
+			"upstream", entry.route.Target.String(),
+		)
+
+		// Dispatch on a clone so the strip-prefix/header rewrites below are
+		// only visible downstream, not on the shared *http.Request the
+		// logging middleware inspects after ServeHTTP returns.
+		forwardReq := r.Clone(r.Context())
+		forwardReq.URL.Path = stripPathPrefix(r.URL.Path, entry.route.StripPrefix)
+		for header, value := range entry.route.HeaderRewrites {
+			forwardReq.Header.Set(header, value)
+		}
+
+		token, ok, err := entry.auth.Validate(forwardReq)
+		if err != nil {
+			utils.HandleError(w, r, err, http.StatusUnauthorized)
+			return
+		}
+		if !ok {
+			utils.HandleError(w, r, fmt.Errorf("request not authorized"), http.StatusUnauthorized)
+			return
+		}
+		if token != "" {
+			forwardReq.Header.Set(config.AuthTokenHeader, token)
+		}
+
+		entry.proxy.ServeHTTP(w, forwardReq)
+		return
+	}
+
+	router.fallback.HandleProxy(w, r)
+}
@@ -0,0 +1,129 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"my-proxy-service/internal/auth"
+	"my-proxy-service/internal/config"
+)
+
+func writeRoutesFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRouterDispatchesMatchingRouteWithStripPrefixAndHeaderRewrites(t *testing.T) {
+	var gotPath string
+	var gotHeader http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	prevHeader := config.AuthTokenHeader
+	config.AuthTokenHeader = "Authorization"
+	t.Cleanup(func() { config.AuthTokenHeader = prevHeader })
+
+	routesPath := writeRoutesFile(t, fmt.Sprintf(`
+auth_profiles:
+  svcA: static://?token=abc123&user=svcA
+routes:
+  - path_pattern: "^/api/"
+    target: %q
+    auth_profile: svcA
+    strip_prefix: /api
+    header_rewrites:
+      X-Injected: "yes"
+`, upstream.URL))
+
+	router, err := NewRouter(routesPath, auth.NewNoneAuth())
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/widgets", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d", rr.Code)
+	}
+	if gotPath != "/widgets" {
+		t.Fatalf("expected strip_prefix to remove /api, upstream saw %q", gotPath)
+	}
+	if gotHeader.Get("X-Injected") != "yes" {
+		t.Fatal("expected header_rewrites to be applied to the forwarded request")
+	}
+	if gotHeader.Get("Authorization") == "" {
+		t.Fatal("expected the matched route's auth_profile to set the auth token header")
+	}
+	if req.URL.Path != "/api/widgets" {
+		t.Fatalf("expected the original request's URL to be left untouched, got %q", req.URL.Path)
+	}
+}
+
+func TestRouterFallsBackToProxyTargetWhenNoRouteMatches(t *testing.T) {
+	var hit bool
+	fallbackUpstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fallbackUpstream.Close()
+	withProxyTarget(t, fallbackUpstream.URL)
+
+	routesPath := writeRoutesFile(t, "routes: []\n")
+
+	router, err := NewRouter(routesPath, auth.NewNoneAuth())
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/anything", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if !hit {
+		t.Fatal("expected an unmatched request to fall back to config.ProxyTarget")
+	}
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d", rr.Code)
+	}
+}
+
+func TestRouterUnknownAuthProfileFallsBackToDefaultAuth(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	routesPath := writeRoutesFile(t, fmt.Sprintf(`
+routes:
+  - path_pattern: "^/api/"
+    target: %q
+    auth_profile: does-not-exist
+`, upstream.URL))
+
+	router, err := NewRouter(routesPath, auth.NewStaticAuth("", ""))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/widgets", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d (default auth should have rejected the request)", rr.Code, http.StatusUnauthorized)
+	}
+}
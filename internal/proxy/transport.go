@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"my-proxy-service/internal/config"
+)
+
+var (
+	sharedTransportOnce sync.Once
+	sharedTransportInst *http.Transport
+)
+
+// getSharedTransport returns the http.Transport used by every reverse
+// proxy the package builds, so upstream connections are pooled across
+// routes instead of being dialed fresh per request. It is built lazily
+// on first use (rather than as a package-level var) so it picks up
+// config values set by config.LoadConfig() instead of baking in flag
+// defaults from before flag.Parse() ran.
+func getSharedTransport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		sharedTransportInst = newTransport()
+	})
+	return sharedTransportInst
+}
+
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: time.Duration(config.DialTimeoutMs) * time.Millisecond,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       time.Duration(config.IdleConnTimeoutMs) * time.Millisecond,
+		TLSHandshakeTimeout:   time.Duration(config.TLSHandshakeTimeoutMs) * time.Millisecond,
+		ResponseHeaderTimeout: time.Duration(config.ResponseHeaderTimeoutMs) * time.Millisecond,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+func flushInterval() time.Duration {
+	return time.Duration(config.FlushIntervalMs) * time.Millisecond
+}
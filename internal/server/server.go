@@ -0,0 +1,140 @@
+// Package server builds the proxy's top-level *http.Server, including
+// optional TLS/mTLS, and runs it with graceful shutdown on SIGINT/SIGTERM.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	applog "my-proxy-service/internal/log"
+)
+
+// Options configures the HTTP server built by New. The shutdown grace
+// period is not part of Options since it governs RunUntilSignal, not the
+// *http.Server itself; pass it there directly.
+type Options struct {
+	Addr           string
+	Handler        http.Handler
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCA, when set, requires and verifies a client certificate
+	// signed by this CA bundle (mTLS).
+	TLSClientCA string
+	// TLSMinVersion is one of "1.0", "1.1", "1.2" or "1.3".
+	TLSMinVersion string
+}
+
+// New builds an *http.Server from opts, including a *tls.Config when
+// TLSCertFile is set.
+func New(opts Options) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:           opts.Addr,
+		Handler:        opts.Handler,
+		ReadTimeout:    opts.ReadTimeout,
+		WriteTimeout:   opts.WriteTimeout,
+		IdleTimeout:    opts.IdleTimeout,
+		MaxHeaderBytes: opts.MaxHeaderBytes,
+	}
+
+	if opts.TLSCertFile == "" && opts.TLSKeyFile == "" {
+		return srv, nil
+	}
+	if opts.TLSCertFile == "" || opts.TLSKeyFile == "" {
+		return nil, fmt.Errorf("tls-cert and tls-key must both be set to enable TLS")
+	}
+
+	minVersion, err := parseTLSVersion(opts.TLSMinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{MinVersion: minVersion}
+
+	if opts.TLSClientCA != "" {
+		pem, err := os.ReadFile(opts.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("reading tls client ca %q: %w", opts.TLSClientCA, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in tls client ca %q", opts.TLSClientCA)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	srv.TLSConfig = tlsConfig
+	return srv, nil
+}
+
+// parseTLSVersion maps a "1.0".."1.3" flag value to a tls.VersionTLS1x
+// constant.
+func parseTLSVersion(name string) (uint16, error) {
+	switch name {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2", "":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unknown tls-min-version %q", name)
+	}
+}
+
+// RunUntilSignal starts srv (serving TLS when srv.TLSConfig is set) and
+// blocks until SIGINT or SIGTERM, at which point it gives in-flight
+// requests up to gracePeriod to finish before closing remaining
+// connections.
+func RunUntilSignal(srv *http.Server, certFile, keyFile string, gracePeriod time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case s := <-sig:
+		applog.L().Info("shutting down", "signal", s.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("graceful shutdown: %w", err)
+	}
+	return <-serveErr
+}
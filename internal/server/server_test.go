@@ -0,0 +1,132 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	if _, err := parseTLSVersion("1.4"); err == nil {
+		t.Fatal("expected an error for an unknown tls version")
+	}
+
+	for _, name := range []string{"", "1.0", "1.1", "1.2", "1.3"} {
+		if _, err := parseTLSVersion(name); err != nil {
+			t.Fatalf("parseTLSVersion(%q): %v", name, err)
+		}
+	}
+}
+
+// writeTestCA writes a self-signed CA certificate (PEM) to dir/ca.pem and
+// returns its path.
+func writeTestCA(t *testing.T, dir string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating CA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating CA certificate: %v", err)
+	}
+
+	path := filepath.Join(dir, "ca.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(path, pemBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewWithClientCAEnablesMTLS(t *testing.T) {
+	caPath := writeTestCA(t, t.TempDir())
+
+	srv, err := New(Options{
+		Addr:        ":0",
+		Handler:     http.NewServeMux(),
+		TLSCertFile: "cert.pem",
+		TLSKeyFile:  "key.pem",
+		TLSClientCA: caPath,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if srv.TLSConfig == nil {
+		t.Fatal("expected a non-nil TLSConfig")
+	}
+	if srv.TLSConfig.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("MinVersion = %v, want TLS 1.2", srv.TLSConfig.MinVersion)
+	}
+	if srv.TLSConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatalf("ClientAuth = %v, want RequireAndVerifyClientCert", srv.TLSConfig.ClientAuth)
+	}
+	if srv.TLSConfig.ClientCAs == nil {
+		t.Fatal("expected ClientCAs to be populated from TLSClientCA")
+	}
+}
+
+func TestNewWithoutClientCADoesNotRequireClientCert(t *testing.T) {
+	srv, err := New(Options{
+		Addr:        ":0",
+		Handler:     http.NewServeMux(),
+		TLSCertFile: "cert.pem",
+		TLSKeyFile:  "key.pem",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if srv.TLSConfig.ClientAuth != tls.NoClientCert {
+		t.Fatalf("ClientAuth = %v, want NoClientCert", srv.TLSConfig.ClientAuth)
+	}
+	if srv.TLSConfig.ClientCAs != nil {
+		t.Fatal("expected ClientCAs to be nil without TLSClientCA")
+	}
+}
+
+func TestNewRejectsCertWithoutKey(t *testing.T) {
+	if _, err := New(Options{TLSCertFile: "cert.pem"}); err == nil {
+		t.Fatal("expected an error when tls-key is missing")
+	}
+}
+
+func TestNewRejectsKeyWithoutCert(t *testing.T) {
+	if _, err := New(Options{TLSKeyFile: "key.pem"}); err == nil {
+		t.Fatal("expected an error when tls-cert is missing")
+	}
+}
+
+func TestNewRejectsUnreadableClientCA(t *testing.T) {
+	_, err := New(Options{
+		TLSCertFile: "cert.pem",
+		TLSKeyFile:  "key.pem",
+		TLSClientCA: filepath.Join(t.TempDir(), "missing.pem"),
+	})
+	if err == nil {
+		t.Fatal("expected an error when the client CA file cannot be read")
+	}
+}
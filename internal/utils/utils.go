@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"net/http"
+
+	applog "my-proxy-service/internal/log"
+)
+
+// HandleError logs the error with the request's fields and writes it to
+// the client as a plain HTTP error response with the given status code.
+func HandleError(w http.ResponseWriter, r *http.Request, err error, code int) {
+	applog.L().Error("request failed",
+		"req_id", applog.RequestID(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", code,
+		"error", err,
+	)
+	http.Error(w, err.Error(), code)
+}